@@ -0,0 +1,99 @@
+package giturl
+
+import "fmt"
+
+// FormatOptions configures (*GitURL).To, replacing the divergent parameter
+// lists of the individual ToXxxFormat methods (SSH takes user+port, HTTP
+// takes port+secure, SCP takes only user) with a single, protocol-agnostic
+// set of knobs.
+type FormatOptions struct {
+	// User overrides the user segment. Ignored if WithCredentials is set.
+	User string
+	// Port overrides the port segment. ImplicitPort omits it.
+	Port uint16
+	// Secure picks the secure variant of a protocol family, i.e. https
+	// instead of http, or ftps instead of ftp.
+	Secure bool
+	// WithSuffix appends ".git" to the repo path.
+	WithSuffix bool
+	// WithCredentials embeds g.User()/g.Password() instead of User.
+	WithCredentials bool
+	// TrailingSlash appends a trailing "/" to the repo path.
+	TrailingSlash bool
+}
+
+// To renders g as protocol p according to opts. It subsumes the
+// ToSSHFormat/ToGitFormat/ToHTTPFormat/ToFTPFormat/ToSCPFormat methods,
+// which are now thin wrappers around it.
+func (g GitURL) To(p ProtocolType, opts FormatOptions) string {
+	if p == ProtocolTypeSCP {
+		return g.toSCP(opts)
+	}
+
+	prefix, ok := g.formatPrefix(p, opts.Secure)
+	if !ok {
+		return ""
+	}
+
+	hostPart := g.bracketedHost()
+	if opts.Port != ImplicitPort {
+		hostPart += fmt.Sprintf(":%d", opts.Port)
+	}
+
+	return prefix + g.credentialPart(opts) + hostPart + g.repoPart("/", opts)
+}
+
+func (g GitURL) formatPrefix(p ProtocolType, secure bool) (string, bool) {
+	switch p {
+	case ProtocolTypeSSH:
+		return prefixSSH, true
+	case ProtocolTypeGit:
+		return prefixGit, true
+	case ProtocolTypeHTTP, ProtocolTypeHTTPs:
+		if secure || p == ProtocolTypeHTTPs {
+			return prefixHTTPs, true
+		}
+		return prefixHTTP, true
+	case ProtocolTypeFTP, ProtocolTypeFTPs:
+		if secure || p == ProtocolTypeFTPs {
+			return prefixFTPs, true
+		}
+		return prefixFTP, true
+	default:
+		return "", false
+	}
+}
+
+// format: [user@]host.xz:path/to/repo.git
+func (g GitURL) toSCP(opts FormatOptions) string {
+	return g.credentialPart(opts) + g.bracketedHost() + g.repoPart(":", opts)
+}
+
+// repoPart renders the sep-prefixed repo path, with any requested ".git"
+// suffix, trailing slash, and subpath/query/fragment. sep is "/" for
+// scheme-prefixed protocols and ":" for SCP-style ones.
+func (g GitURL) repoPart(sep string, opts FormatOptions) string {
+	part := fmt.Sprintf("%s%s/%s", sep, g.path, g.repo)
+	if opts.WithSuffix {
+		part += suffixGit
+	}
+	if opts.TrailingSlash {
+		part += "/"
+	}
+	part += g.suffixPart()
+	return part
+}
+
+func (g GitURL) credentialPart(opts FormatOptions) string {
+	user, password := opts.User, ""
+	if opts.WithCredentials {
+		user, password = g.user, g.password
+	}
+	if user == ImplicitUser {
+		return ""
+	}
+	if password != "" {
+		return user + ":" + password + "@"
+	}
+	return user + "@"
+}
@@ -1,6 +1,10 @@
 package giturl
 
-import "fmt"
+import (
+	"fmt"
+	"net"
+	"strings"
+)
 
 type ProtocolType int8
 
@@ -39,13 +43,28 @@ const (
 	suffixGit   = ".git"
 )
 
+const (
+	schemeSSH   = "ssh"
+	schemeGit   = "git"
+	schemeHTTP  = "http"
+	schemeHTTPs = "https"
+	schemeFTP   = "ftp"
+	schemeFTPs  = "ftps"
+	schemeSCP   = "scp"
+)
+
 type GitURL struct {
 	protocol ProtocolType
 	port     uint16
 	user     string
+	password string
 	host     string
 	path     string
 	repo     string
+	subPath  string
+	query    string
+	fragment string
+	ref      string
 	raw      string
 }
 
@@ -65,6 +84,10 @@ func (g GitURL) User() string {
 	return g.user
 }
 
+func (g GitURL) Password() string {
+	return g.password
+}
+
 func (g GitURL) Host() string {
 	return g.host
 }
@@ -77,89 +100,143 @@ func (g GitURL) Repo() string {
 	return g.repo
 }
 
+// IsIPv6 reports whether Host is an IPv6 literal, e.g. "::1" or
+// "2001:db8::1", as opposed to a hostname or IPv4 address.
+func (g GitURL) IsIPv6() bool {
+	return strings.Contains(g.host, ":") && net.ParseIP(g.host) != nil
+}
+
+// HostPort renders Host and Port as a dial-ready "host:port" string,
+// bracketing IPv6 literals (e.g. "[::1]:22") so the literal's own colons
+// can't be confused with the port separator. When Port is 0 only the
+// (bracketed) host is returned.
+func (g GitURL) HostPort() string {
+	host := g.bracketedHost()
+	if g.port == ImplicitPort {
+		return host
+	}
+	return fmt.Sprintf("%s:%d", host, g.port)
+}
+
+// bracketedHost returns Host as-is, unless it's an IPv6 literal, in which
+// case it's wrapped in brackets for safe use in a formatted URL.
+func (g GitURL) bracketedHost() string {
+	if g.IsIPv6() {
+		return "[" + g.host + "]"
+	}
+	return g.host
+}
+
+// SubPath is the directory within the repository that a Kustomize-style
+// double slash (repo.git//path/to/dir) points at, if any.
+func (g GitURL) SubPath() string {
+	return g.subPath
+}
+
+// Query is the raw query string (without the leading '?'), e.g. "ref=v1.2".
+func (g GitURL) Query() string {
+	return g.query
+}
+
+// Fragment is the raw fragment (without the leading '#').
+func (g GitURL) Fragment() string {
+	return g.fragment
+}
+
+// Ref is the value of the "ref" query parameter, if present, as used by
+// tools like Kustomize to pin a branch, tag, or commit.
+func (g GitURL) Ref() string {
+	return g.ref
+}
+
+// RawURL returns the exact string NewGitURL was given, with any embedded
+// password masked out. Use Password() to retrieve the credential itself.
 func (g GitURL) RawURL() string {
-	return g.raw
+	return maskPassword(g.raw, g.password)
 }
 
-// format: ssh://[user@]host.xz[:port]/path/to/repo.git
-func (g GitURL) ToSSHFormat(user string, port uint16, withSuffix bool) string {
-	var userPart, hostPart, pathPart string
-	if user != ImplicitUser {
-		userPart = user + "@"
+// String implements fmt.Stringer. Like RawURL, it masks any embedded
+// password.
+func (g GitURL) String() string {
+	return g.RawURL()
+}
+
+// WithCredentials returns a copy of g with user and password set, for
+// callers that parsed a URL without credentials and want to attach them
+// before formatting it.
+func (g GitURL) WithCredentials(user, password string) *GitURL {
+	newURL := g
+	newURL.user = user
+	newURL.password = password
+	return &newURL
+}
+
+// WithRef returns a copy of g pinned to ref, as the "ref" query parameter.
+func (g GitURL) WithRef(ref string) *GitURL {
+	newURL := g
+	newURL.ref = ref
+	var pairs []string
+	if newURL.query != "" {
+		for _, pair := range strings.Split(newURL.query, "&") {
+			if key, _, _ := cut(pair, "="); key != "ref" {
+				pairs = append(pairs, pair)
+			}
+		}
 	}
-	hostPart = g.host
-	if port != ImplicitPort {
-		hostPart += fmt.Sprintf(":%d", port)
+	if ref != "" {
+		pairs = append(pairs, "ref="+ref)
 	}
-	pathPart = fmt.Sprintf("/%s/%s", g.path, g.repo)
-	if withSuffix {
-		pathPart += suffixGit
+	newURL.query = strings.Join(pairs, "&")
+	return &newURL
+}
+
+func maskPassword(raw, password string) string {
+	if password == "" {
+		return raw
 	}
-	return prefixSSH + userPart + hostPart + pathPart
+	return strings.Replace(raw, ":"+password+"@", ":***@", 1)
 }
 
-// format: git://host.xz[:port]/path/to/repo.git
-func (g GitURL) ToGitFormat(port uint16, withSuffix bool) string {
-	var hostPart, pathPart string
-	hostPart = g.host
-	if port != ImplicitPort {
-		hostPart += fmt.Sprintf(":%d", port)
+// suffixPart reassembles the subpath, query, and fragment trailing a
+// formatted URL's repo path, e.g. "//sub/dir?ref=v1.2#section".
+func (g GitURL) suffixPart() string {
+	var b strings.Builder
+	if g.subPath != "" {
+		b.WriteString("//")
+		b.WriteString(g.subPath)
 	}
-	pathPart = fmt.Sprintf("/%s/%s", g.path, g.repo)
-	if withSuffix {
-		pathPart += suffixGit
+	if g.query != "" {
+		b.WriteString("?")
+		b.WriteString(g.query)
 	}
-	return prefixGit + hostPart + pathPart
+	if g.fragment != "" {
+		b.WriteString("#")
+		b.WriteString(g.fragment)
+	}
+	return b.String()
+}
+
+// format: ssh://[user@]host.xz[:port]/path/to/repo.git
+func (g GitURL) ToSSHFormat(user string, port uint16, withSuffix bool) string {
+	return g.To(ProtocolTypeSSH, FormatOptions{User: user, Port: port, WithSuffix: withSuffix})
+}
+
+// format: git://host.xz[:port]/path/to/repo.git
+func (g GitURL) ToGitFormat(port uint16, withSuffix bool) string {
+	return g.To(ProtocolTypeGit, FormatOptions{Port: port, WithSuffix: withSuffix})
 }
 
 // format: http[s]://host.xz[:port]/path/to/repo.git
 func (g GitURL) ToHTTPFormat(port uint16, isSecure bool, withSuffix bool) string {
-	var prefixPart, hostPart, pathPart string
-	if isSecure {
-		prefixPart = prefixHTTPs
-	} else {
-		prefixPart = prefixHTTP
-	}
-	hostPart = g.host
-	if port != ImplicitPort {
-		hostPart += fmt.Sprintf(":%d", port)
-	}
-	pathPart = fmt.Sprintf("/%s/%s", g.path, g.repo)
-	if withSuffix {
-		pathPart += suffixGit
-	}
-	return prefixPart + hostPart + pathPart
+	return g.To(ProtocolTypeHTTP, FormatOptions{Port: port, Secure: isSecure, WithSuffix: withSuffix})
 }
 
 // format: ftp[s]://host.xz[:port]/path/to/repo.git
 func (g GitURL) ToFTPFormat(port uint16, isSecure bool, withSuffix bool) string {
-	var prefixPart, hostPart, pathPart string
-	if isSecure {
-		prefixPart = prefixFTPs
-	} else {
-		prefixPart = prefixFTP
-	}
-	hostPart = g.host
-	if port != ImplicitPort {
-		hostPart += fmt.Sprintf(":%d", port)
-	}
-	pathPart = fmt.Sprintf("/%s/%s", g.path, g.repo)
-	if withSuffix {
-		pathPart += suffixGit
-	}
-	return prefixPart + hostPart + pathPart
+	return g.To(ProtocolTypeFTP, FormatOptions{Port: port, Secure: isSecure, WithSuffix: withSuffix})
 }
 
 // format: [user@]host.xz:path/to/repo.git
 func (g GitURL) ToSCPFormat(user string, withSuffix bool) string {
-	var userPart, pathPart string
-	if user != ImplicitUser {
-		userPart = user + "@"
-	}
-
-	pathPart = fmt.Sprintf(":%s/%s", g.path, g.repo)
-	if withSuffix {
-		pathPart += suffixGit
-	}
-	return userPart + g.host + pathPart
+	return g.To(ProtocolTypeSCP, FormatOptions{User: user, WithSuffix: withSuffix})
 }
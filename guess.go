@@ -0,0 +1,81 @@
+package giturl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Style selects the wire format GuessGitURL renders a shorthand into.
+type Style int8
+
+const (
+	StyleSSH Style = iota
+	StyleHTTPS
+)
+
+// GuessOptions configures how GuessGitURL expands a shorthand repo spec.
+type GuessOptions struct {
+	// Style picks whether the resulting GitURL is formatted as SSH or HTTPS.
+	Style Style
+	// DefaultHost is used when the shorthand carries no host alias, e.g.
+	// "torvalds/linux" with DefaultHost "github.com".
+	DefaultHost string
+	// DefaultUser fills in the repo owner when the shorthand is a bare repo
+	// name, e.g. "linux" with DefaultUser "torvalds".
+	DefaultUser string
+}
+
+// shorthandHosts maps the host aliases borrowed from chezmoi's init command
+// and similar tools to the host they stand for.
+var shorthandHosts = map[string]string{
+	"gh": "github.com",
+	"gl": "gitlab.com",
+	"bb": "bitbucket.org",
+	"sr": "sr.ht",
+}
+
+// GuessGitURL expands a shorthand repo spec such as "torvalds/linux",
+// "gh:torvalds/linux", "gl:group/sub/proj", or a bare "linux" (resolved
+// against opts.DefaultUser) into a fully-populated GitURL.
+func GuessGitURL(shorthand string, opts GuessOptions) (*GitURL, error) {
+	if shorthand == "" {
+		return nil, fmt.Errorf("%w, empty shorthand", ErrInvalidURL)
+	}
+
+	host := opts.DefaultHost
+	rest := shorthand
+	if alias, path, ok := cut(shorthand, ":"); ok {
+		if known, exists := shorthandHosts[alias]; exists {
+			host, rest = known, path
+		}
+	}
+	if host == "" {
+		return nil, fmt.Errorf("%w, no host for shorthand '%s'", ErrInvalidURL, shorthand)
+	}
+
+	if !strings.Contains(rest, "/") {
+		if opts.DefaultUser == "" {
+			return nil, fmt.Errorf("%w, missing owner in shorthand '%s'", ErrInvalidURL, shorthand)
+		}
+		rest = opts.DefaultUser + "/" + rest
+	}
+	path, repo, _ := lastCut(removeSuffix(rest, suffixGit), "/")
+	if repo == "" {
+		return nil, fmt.Errorf("%w, missing repo in shorthand '%s'", ErrInvalidURL, shorthand)
+	}
+
+	gitURL := &GitURL{host: host, path: path, repo: repo}
+	switch opts.Style {
+	case StyleSSH:
+		gitURL.protocol = ProtocolTypeSSH
+		gitURL.port = DefaultSSHPort
+		gitURL.user = DefaultUser
+		gitURL.raw = gitURL.ToSSHFormat(gitURL.user, ImplicitPort, true)
+	default:
+		gitURL.protocol = ProtocolTypeHTTPs
+		gitURL.port = DefaultHTTPsPort
+		gitURL.raw = gitURL.ToHTTPFormat(ImplicitPort, true, true)
+	}
+
+	return gitURL, nil
+}
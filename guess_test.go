@@ -0,0 +1,84 @@
+package giturl
+
+import "testing"
+
+func TestGuessGitURL(t *testing.T) {
+	testCases := []struct {
+		Shorthand string
+		Opts      GuessOptions
+		Host      string
+		Path      string
+		Repo      string
+		Protocol  ProtocolType
+		Err       error
+	}{
+		{
+			Shorthand: "torvalds/linux",
+			Opts:      GuessOptions{Style: StyleHTTPS, DefaultHost: "github.com"},
+			Host:      "github.com",
+			Path:      "torvalds",
+			Repo:      "linux",
+			Protocol:  ProtocolTypeHTTPs,
+		},
+		{
+			Shorthand: "gh:torvalds/linux",
+			Opts:      GuessOptions{Style: StyleSSH, DefaultHost: "example.com"},
+			Host:      "github.com",
+			Path:      "torvalds",
+			Repo:      "linux",
+			Protocol:  ProtocolTypeSSH,
+		},
+		{
+			Shorthand: "gl:group/sub/proj",
+			Opts:      GuessOptions{Style: StyleHTTPS},
+			Host:      "gitlab.com",
+			Path:      "group/sub",
+			Repo:      "proj",
+			Protocol:  ProtocolTypeHTTPs,
+		},
+		{
+			Shorthand: "linux",
+			Opts:      GuessOptions{Style: StyleSSH, DefaultHost: "github.com", DefaultUser: "torvalds"},
+			Host:      "github.com",
+			Path:      "torvalds",
+			Repo:      "linux",
+			Protocol:  ProtocolTypeSSH,
+		},
+		{
+			Shorthand: "linux",
+			Opts:      GuessOptions{Style: StyleSSH, DefaultHost: "github.com"},
+			Err:       ErrInvalidURL,
+		},
+		{
+			Shorthand: "torvalds/linux",
+			Opts:      GuessOptions{Style: StyleSSH},
+			Err:       ErrInvalidURL,
+		},
+	}
+
+	for _, c := range testCases {
+		URL, err := GuessGitURL(c.Shorthand, c.Opts)
+		if c.Err != nil {
+			if err == nil {
+				t.Errorf("test failed, expected error for shorthand '%s'", c.Shorthand)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for shorthand '%s': %s", c.Shorthand, err.Error())
+			continue
+		}
+		if actual, expected := URL.Host(), c.Host; actual != expected {
+			t.Errorf("test failed, actual host: %s, expected: %s", actual, expected)
+		}
+		if actual, expected := URL.Path(), c.Path; actual != expected {
+			t.Errorf("test failed, actual path: %s, expected: %s", actual, expected)
+		}
+		if actual, expected := URL.Repo(), c.Repo; actual != expected {
+			t.Errorf("test failed, actual repo: %s, expected: %s", actual, expected)
+		}
+		if actual, expected := URL.Protocol(), c.Protocol; actual != expected {
+			t.Errorf("test failed, actual protocol: %d, expected: %d", actual, expected)
+		}
+	}
+}
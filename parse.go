@@ -11,17 +11,11 @@ var ErrInvalidURL = errors.New("invalid url")
 
 // doc: https://www.git-scm.com/docs/git-clone#URLS
 func parseURL(url string) (*GitURL, error) {
-	if hasPrefix(url, prefixSSH) {
-		return parseSSHURL(url)
-	} else if hasPrefix(url, prefixGit) {
-		return parseGitURL(url)
-	} else if hasPrefix(url, prefixHTTP) || hasPrefix(url, prefixHTTPs) {
-		return parseHTTPURL(url)
-	} else if hasPrefix(url, prefixFTP) || hasPrefix(url, prefixFTPs) {
-		return parseFTPURL(url)
-	} else {
-		return parseSCPURL(url)
+	handler, err := lookupProtocol(url)
+	if err != nil {
+		return nil, err
 	}
+	return handler.Parse(url)
 }
 
 func parseSSHURL(url string) (*GitURL, error) {
@@ -31,11 +25,12 @@ func parseSSHURL(url string) (*GitURL, error) {
 	}
 
 	// pre-processing
-	left := removePrefix(removeSuffix(url, suffixGit), prefixSSH)
+	base, query, fragment := splitQueryFragment(url)
+	gitURL.query, gitURL.fragment, gitURL.ref = query, fragment, extractRef(query)
+	left := removePrefix(base, prefixSSH)
 
-	user, after, ok := cut(left, "@")
-	if ok {
-		gitURL.user = user
+	if user, after, ok := cut(left, "@"); ok {
+		gitURL.user, gitURL.password, _ = cut(user, ":")
 		left = after
 	}
 
@@ -44,7 +39,7 @@ func parseSSHURL(url string) (*GitURL, error) {
 		return nil, fmt.Errorf("%w, missing path to repo", ErrInvalidURL)
 	}
 
-	host, port, ok := cut(before, ":")
+	host, port, ok := splitHostPort(before)
 	if ok {
 		if p, err := strconv.ParseUint(port, 10, 16); err != nil {
 			return nil, fmt.Errorf("%w, illegal port '%s'", ErrInvalidURL, port)
@@ -55,7 +50,11 @@ func parseSSHURL(url string) (*GitURL, error) {
 		gitURL.port = DefaultSSHPort
 	}
 	gitURL.host = host
-	gitURL.path, gitURL.repo, _ = lastCut(left, "/")
+
+	repoPath, subPath := cutSubPath(strings.TrimSuffix(left, "/"))
+	repoPath = removeSuffix(repoPath, suffixGit)
+	gitURL.path, gitURL.repo, _ = lastCut(repoPath, "/")
+	gitURL.subPath = subPath
 
 	return gitURL, nil
 }
@@ -66,14 +65,16 @@ func parseGitURL(url string) (*GitURL, error) {
 		raw:      url,
 	}
 
-	left := removePrefix(removeSuffix(url, suffixGit), prefixGit)
+	base, query, fragment := splitQueryFragment(url)
+	gitURL.query, gitURL.fragment, gitURL.ref = query, fragment, extractRef(query)
+	left := removePrefix(base, prefixGit)
 
 	before, left, ok := cut(left, "/")
 	if !ok {
 		return nil, fmt.Errorf("%w, missing path to repo", ErrInvalidURL)
 	}
 
-	host, port, ok := cut(before, ":")
+	host, port, ok := splitHostPort(before)
 	if ok {
 		if p, err := strconv.ParseUint(port, 10, 16); err != nil {
 			return nil, fmt.Errorf("%w, illegal port '%s'", ErrInvalidURL, port)
@@ -84,7 +85,11 @@ func parseGitURL(url string) (*GitURL, error) {
 		gitURL.port = DefaultGitPort
 	}
 	gitURL.host = host
-	gitURL.path, gitURL.repo, _ = lastCut(left, "/")
+
+	repoPath, subPath := cutSubPath(strings.TrimSuffix(left, "/"))
+	repoPath = removeSuffix(repoPath, suffixGit)
+	gitURL.path, gitURL.repo, _ = lastCut(repoPath, "/")
+	gitURL.subPath = subPath
 
 	return gitURL, nil
 }
@@ -93,22 +98,30 @@ func parseHTTPURL(url string) (*GitURL, error) {
 	gitURL := &GitURL{
 		raw: url,
 	}
-	left := removeSuffix(url, suffixGit)
-	if hasPrefix(url, prefixHTTP) {
+	base, query, fragment := splitQueryFragment(url)
+	gitURL.query, gitURL.fragment, gitURL.ref = query, fragment, extractRef(query)
+
+	left := base
+	if hasPrefix(base, prefixHTTP) {
 		gitURL.protocol = ProtocolTypeHTTP
 		left = removePrefix(left, prefixHTTP)
 	}
-	if hasPrefix(url, prefixHTTPs) {
+	if hasPrefix(base, prefixHTTPs) {
 		gitURL.protocol = ProtocolTypeHTTPs
 		left = removePrefix(left, prefixHTTPs)
 	}
 
+	if user, after, ok := cut(left, "@"); ok {
+		gitURL.user, gitURL.password, _ = cut(user, ":")
+		left = after
+	}
+
 	before, left, ok := cut(left, "/")
 	if !ok {
 		return nil, fmt.Errorf("%w, missing path to repo", ErrInvalidURL)
 	}
 
-	host, port, ok := cut(before, ":")
+	host, port, ok := splitHostPort(before)
 	if ok {
 		if p, err := strconv.ParseUint(port, 10, 16); err != nil {
 			return nil, fmt.Errorf("%w, illegal port '%s'", ErrInvalidURL, port)
@@ -124,7 +137,11 @@ func parseHTTPURL(url string) (*GitURL, error) {
 		}
 	}
 	gitURL.host = host
-	gitURL.path, gitURL.repo, _ = lastCut(left, "/")
+
+	repoPath, subPath := cutSubPath(strings.TrimSuffix(left, "/"))
+	repoPath = removeSuffix(repoPath, suffixGit)
+	gitURL.path, gitURL.repo, _ = lastCut(repoPath, "/")
+	gitURL.subPath = subPath
 
 	return gitURL, nil
 }
@@ -133,22 +150,30 @@ func parseFTPURL(url string) (*GitURL, error) {
 	gitURL := &GitURL{
 		raw: url,
 	}
-	left := removeSuffix(url, suffixGit)
-	if hasPrefix(url, prefixFTP) {
+	base, query, fragment := splitQueryFragment(url)
+	gitURL.query, gitURL.fragment, gitURL.ref = query, fragment, extractRef(query)
+
+	left := base
+	if hasPrefix(base, prefixFTP) {
 		gitURL.protocol = ProtocolTypeFTP
 		left = removePrefix(left, prefixFTP)
 	}
-	if hasPrefix(url, prefixFTPs) {
+	if hasPrefix(base, prefixFTPs) {
 		gitURL.protocol = ProtocolTypeFTPs
 		left = removePrefix(left, prefixFTPs)
 	}
 
+	if user, after, ok := cut(left, "@"); ok {
+		gitURL.user, gitURL.password, _ = cut(user, ":")
+		left = after
+	}
+
 	before, left, ok := cut(left, "/")
 	if !ok {
 		return nil, fmt.Errorf("%w, missing path to repo", ErrInvalidURL)
 	}
 
-	host, port, ok := cut(before, ":")
+	host, port, ok := splitHostPort(before)
 	if ok {
 		if p, err := strconv.ParseUint(port, 10, 16); err != nil {
 			return nil, fmt.Errorf("%w, illegal port '%s'", ErrInvalidURL, port)
@@ -164,7 +189,11 @@ func parseFTPURL(url string) (*GitURL, error) {
 		}
 	}
 	gitURL.host = host
-	gitURL.path, gitURL.repo, _ = lastCut(left, "/")
+
+	repoPath, subPath := cutSubPath(strings.TrimSuffix(left, "/"))
+	repoPath = removeSuffix(repoPath, suffixGit)
+	gitURL.path, gitURL.repo, _ = lastCut(repoPath, "/")
+	gitURL.subPath = subPath
 
 	return gitURL, nil
 }
@@ -176,20 +205,28 @@ func parseSCPURL(url string) (*GitURL, error) {
 		raw:      url,
 	}
 
-	left := removeSuffix(url, suffixGit)
+	base, query, fragment := splitQueryFragment(url)
+	gitURL.query, gitURL.fragment, gitURL.ref = query, fragment, extractRef(query)
+	left := base
 
-	user, after, ok := cut(left, "@")
-	if ok {
-		gitURL.user = user
+	if user, after, ok := cut(left, "@"); ok {
+		gitURL.user, gitURL.password, _ = cut(user, ":")
 		left = after
 	}
 
-	host, left, ok := cut(left, ":")
+	host, left, ok := cutSCPHost(left)
 	if !ok {
 		return nil, fmt.Errorf("%w, expected ':'", ErrInvalidURL)
 	}
+	if !isValidSCPHost(host) {
+		return nil, fmt.Errorf("%w, '%s' looks like a path, not a host", ErrInvalidURL, host)
+	}
 	gitURL.host = host
-	gitURL.path, gitURL.repo, _ = lastCut(left, "/")
+
+	repoPath, subPath := cutSubPath(strings.TrimSuffix(left, "/"))
+	repoPath = removeSuffix(repoPath, suffixGit)
+	gitURL.path, gitURL.repo, _ = lastCut(repoPath, "/")
+	gitURL.subPath = subPath
 
 	return gitURL, nil
 }
@@ -236,3 +273,79 @@ func lastCut(s, sep string) (before, after string, found bool) {
 	}
 	return "", s, false
 }
+
+// splitQueryFragment peels the fragment and query off the tail of a raw URL,
+// e.g. "host/repo.git?ref=v1.2#section" -> ("host/repo.git", "ref=v1.2", "section").
+func splitQueryFragment(raw string) (base, query, fragment string) {
+	base, fragment, _ = cut(raw, "#")
+	base, query, _ = cut(base, "?")
+	return base, query, fragment
+}
+
+// extractRef returns the "ref" query parameter's value, as used by tools
+// like Kustomize's RepoSpec to pin a branch, tag, or commit.
+func extractRef(query string) string {
+	if query == "" {
+		return ""
+	}
+	for _, pair := range strings.Split(query, "&") {
+		if key, value, ok := cut(pair, "="); ok && key == "ref" {
+			return value
+		}
+	}
+	return ""
+}
+
+// cutSubPath splits a Kustomize-style "repo.git//path/to/dir" path on its
+// first double slash, returning the repo path and the subpath separately.
+func cutSubPath(left string) (repoPath, subPath string) {
+	if i := strings.Index(left, "//"); i >= 0 {
+		return left[:i], strings.Trim(left[i+2:], "/")
+	}
+	return left, ""
+}
+
+// splitHostPort cuts a "host[:port]" authority chunk, accepting a
+// bracketed IPv6 literal such as "[::1]:22" so the literal's own colons
+// aren't mistaken for the port separator.
+func splitHostPort(before string) (host, port string, ok bool) {
+	if strings.HasPrefix(before, "[") {
+		if i := strings.Index(before, "]"); i >= 0 {
+			if strings.HasPrefix(before[i+1:], ":") {
+				return before[1:i], before[i+2:], true
+			}
+			return before[1:i], "", false
+		}
+	}
+	return cut(before, ":")
+}
+
+// cutSCPHost cuts the "host" out of an SCP-style "host:path" spec, accepting
+// a bracketed IPv6 literal the same way splitHostPort does.
+func cutSCPHost(left string) (host, rest string, ok bool) {
+	if strings.HasPrefix(left, "[") {
+		if i := strings.Index(left, "]"); i >= 0 && strings.HasPrefix(left[i+1:], ":") {
+			return left[1:i], left[i+2:], true
+		}
+		return "", "", false
+	}
+	return cut(left, ":")
+}
+
+// isValidSCPHost rejects "hosts" that are really an absolute path in
+// disguise, following the disambiguation go-git's internal/url package
+// documents: a path separator or a single-letter drive (as in the Windows
+// path "C:\foo") means the input was never SCP-style to begin with.
+func isValidSCPHost(host string) bool {
+	if host == "" || strings.ContainsAny(host, `/\`) {
+		return false
+	}
+	if len(host) == 1 && isASCIILetter(host[0]) {
+		return false
+	}
+	return true
+}
+
+func isASCIILetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
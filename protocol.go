@@ -0,0 +1,107 @@
+package giturl
+
+import "fmt"
+
+// Endpoint describes the minimal set of fields needed to address a remote
+// repository, independent of how its URL was originally written. GitURL
+// implements Endpoint, but third parties registering a new protocol via
+// RegisterProtocol are free to produce their own implementations.
+type Endpoint interface {
+	Protocol() ProtocolType
+	User() string
+	Password() string
+	Host() string
+	Port() uint16
+	Path() string
+}
+
+var _ Endpoint = GitURL{}
+
+// ProtocolHandler bundles everything parseURL needs to recognize and parse a
+// scheme, so that new schemes can be taught to NewGitURL without touching
+// parseURL itself.
+type ProtocolHandler struct {
+	Type        ProtocolType
+	DefaultPort uint16
+	// Prefix is the scheme prefix used to recognize a URL, e.g. "ssh://".
+	// The SCP-style handler leaves this empty since it has no prefix and is
+	// only ever used as the fallback.
+	Prefix string
+	Parse  func(url string) (*GitURL, error)
+	Format func(g *GitURL) string
+}
+
+// Protocols is the registry of schemes known to parseURL, keyed by scheme
+// name (e.g. "ssh", "rsync"). Register new schemes with RegisterProtocol.
+var Protocols = map[string]*ProtocolHandler{}
+
+// RegisterProtocol teaches NewGitURL a new scheme. Registering under an
+// already-known name replaces the existing handler.
+func RegisterProtocol(scheme string, handler *ProtocolHandler) {
+	Protocols[scheme] = handler
+}
+
+func init() {
+	RegisterProtocol(schemeSSH, &ProtocolHandler{
+		Type:        ProtocolTypeSSH,
+		DefaultPort: DefaultSSHPort,
+		Prefix:      prefixSSH,
+		Parse:       parseSSHURL,
+		Format:      func(g *GitURL) string { return g.ToSSHFormat(g.user, g.port, false) },
+	})
+	RegisterProtocol(schemeGit, &ProtocolHandler{
+		Type:        ProtocolTypeGit,
+		DefaultPort: DefaultGitPort,
+		Prefix:      prefixGit,
+		Parse:       parseGitURL,
+		Format:      func(g *GitURL) string { return g.ToGitFormat(g.port, false) },
+	})
+	RegisterProtocol(schemeHTTP, &ProtocolHandler{
+		Type:        ProtocolTypeHTTP,
+		DefaultPort: DefaultHTTPPort,
+		Prefix:      prefixHTTP,
+		Parse:       parseHTTPURL,
+		Format:      func(g *GitURL) string { return g.ToHTTPFormat(g.port, false, false) },
+	})
+	RegisterProtocol(schemeHTTPs, &ProtocolHandler{
+		Type:        ProtocolTypeHTTPs,
+		DefaultPort: DefaultHTTPsPort,
+		Prefix:      prefixHTTPs,
+		Parse:       parseHTTPURL,
+		Format:      func(g *GitURL) string { return g.ToHTTPFormat(g.port, true, false) },
+	})
+	RegisterProtocol(schemeFTP, &ProtocolHandler{
+		Type:        ProtocolTypeFTP,
+		DefaultPort: DefaultFTPPort,
+		Prefix:      prefixFTP,
+		Parse:       parseFTPURL,
+		Format:      func(g *GitURL) string { return g.ToFTPFormat(g.port, false, false) },
+	})
+	RegisterProtocol(schemeFTPs, &ProtocolHandler{
+		Type:        ProtocolTypeFTPs,
+		DefaultPort: DefaultFTPsPort,
+		Prefix:      prefixFTPs,
+		Parse:       parseFTPURL,
+		Format:      func(g *GitURL) string { return g.ToFTPFormat(g.port, true, false) },
+	})
+	RegisterProtocol(schemeSCP, &ProtocolHandler{
+		Type:        ProtocolTypeSCP,
+		DefaultPort: DefaultSSHPort,
+		Parse:       parseSCPURL,
+		Format:      func(g *GitURL) string { return g.ToSCPFormat(g.user, false) },
+	})
+}
+
+// lookupProtocol returns the handler whose Prefix matches url, falling back
+// to the SCP handler when no scheme prefix is present.
+func lookupProtocol(url string) (*ProtocolHandler, error) {
+	for _, h := range Protocols {
+		if h.Prefix != "" && hasPrefix(url, h.Prefix) {
+			return h, nil
+		}
+	}
+	if h, ok := Protocols[schemeSCP]; ok {
+		return h, nil
+	}
+	return nil, fmt.Errorf("%w, unrecognized scheme", ErrInvalidURL)
+}
@@ -0,0 +1,32 @@
+package giturl
+
+import "testing"
+
+func TestRegisterProtocol(t *testing.T) {
+	RegisterProtocol("rsync", &ProtocolHandler{
+		Type:        ProtocolTypeSCP,
+		DefaultPort: 873,
+		Prefix:      "rsync://",
+		Parse: func(url string) (*GitURL, error) {
+			gitURL, err := parseGitURL("git://" + removePrefix(url, "rsync://"))
+			if err != nil {
+				return nil, err
+			}
+			gitURL.raw = url
+			return gitURL, nil
+		},
+		Format: func(g *GitURL) string { return "rsync://" + g.host + "/" + g.path + "/" + g.repo },
+	})
+	defer delete(Protocols, "rsync")
+
+	URL, err := NewGitURL("rsync://example.com/charlie/wto/bomb.git")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if actual, expected := URL.Host(), "example.com"; actual != expected {
+		t.Errorf("test failed, actual host: %s, expected: %s", actual, expected)
+	}
+	if actual, expected := URL.Repo(), "bomb"; actual != expected {
+		t.Errorf("test failed, actual repo: %s, expected: %s", actual, expected)
+	}
+}
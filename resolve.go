@@ -0,0 +1,161 @@
+package giturl
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ProxyConfig configures how (*GitURL).Resolve picks a proxy, mirroring the
+// proxy-aware endpoint resolution in go-git's HTTP/SSH transports.
+type ProxyConfig struct {
+	// HTTPProxy overrides HTTP_PROXY for plain HTTP/FTP endpoints.
+	HTTPProxy string
+	// HTTPSProxy overrides HTTPS_PROXY for HTTPS/FTPS endpoints.
+	HTTPSProxy string
+	// NoProxy overrides NO_PROXY, a comma-separated list of hosts (or
+	// domain suffixes) that should never be proxied.
+	NoProxy string
+	// PerHost overrides the above for a specific host. A present-but-empty
+	// value forces that host to bypass any proxy.
+	PerHost map[string]string
+	// ProxyJump lists SSH jump hosts to tunnel through, in order, mirroring
+	// OpenSSH's -J / ProxyJump. Only the first hop is dialed directly;
+	// tunneling the remaining hops is left to the SSH client.
+	ProxyJump []string
+}
+
+// DialAddress returns the "host:port" a client should dial, bracketing IPv6
+// literals and defaulting the port to the protocol's well-known port when
+// Port is 0.
+func (g GitURL) DialAddress() string {
+	port := g.port
+	if port == ImplicitPort {
+		port = defaultPortFor(g.protocol)
+	}
+	return fmt.Sprintf("%s:%d", g.bracketedHost(), port)
+}
+
+// Resolve returns the concrete address a client should dial for g, along
+// with the proxy URL to tunnel through, if any. SSH and SCP endpoints
+// instead resolve to the first hop of cfg.ProxyJump, if set.
+func (g GitURL) Resolve(cfg ProxyConfig) (net.Addr, *url.URL, error) {
+	if g.protocol == ProtocolTypeSSH || g.protocol == ProtocolTypeSCP {
+		target := g.DialAddress()
+		if len(cfg.ProxyJump) > 0 {
+			target = normalizeSSHJump(cfg.ProxyJump[0])
+		}
+		addr, err := net.ResolveTCPAddr("tcp", target)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w, %s", ErrInvalidURL, err.Error())
+		}
+		return addr, nil, nil
+	}
+
+	proxyURL, err := resolveProxyURL(g, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w, %s", ErrInvalidURL, err.Error())
+	}
+
+	target := g.DialAddress()
+	if proxyURL != nil {
+		target = proxyHostPort(proxyURL)
+	}
+	addr, err := net.ResolveTCPAddr("tcp", target)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w, %s", ErrInvalidURL, err.Error())
+	}
+	return addr, proxyURL, nil
+}
+
+func defaultPortFor(t ProtocolType) uint16 {
+	for _, h := range Protocols {
+		if h.Type == t {
+			return h.DefaultPort
+		}
+	}
+	return ImplicitPort
+}
+
+func resolveProxyURL(g GitURL, cfg ProxyConfig) (*url.URL, error) {
+	if override, ok := cfg.PerHost[g.host]; ok {
+		if override == "" {
+			return nil, nil
+		}
+		return url.Parse(override)
+	}
+
+	if matchNoProxy(g.host, firstNonEmpty(cfg.NoProxy, envAny("NO_PROXY"))) {
+		return nil, nil
+	}
+
+	proxy := cfg.HTTPProxy
+	envKey := "HTTP_PROXY"
+	if g.protocol == ProtocolTypeHTTPs || g.protocol == ProtocolTypeFTPs {
+		proxy = cfg.HTTPSProxy
+		envKey = "HTTPS_PROXY"
+	}
+	proxy = firstNonEmpty(proxy, envAny(envKey))
+	if proxy == "" {
+		return nil, nil
+	}
+	return url.Parse(proxy)
+}
+
+func envAny(key string) string {
+	return firstNonEmpty(os.Getenv(key), os.Getenv(strings.ToLower(key)))
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func matchNoProxy(host, noProxy string) bool {
+	if noProxy == "*" {
+		return true
+	}
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.TrimSpace(strings.TrimPrefix(entry, "."))
+		if entry == "" {
+			continue
+		}
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeSSHJump turns an OpenSSH-style "[user@]host[:port]" jump spec
+// into a dialable "host:port", defaulting to DefaultSSHPort.
+func normalizeSSHJump(jump string) string {
+	hostPort := jump
+	if _, after, ok := cut(jump, "@"); ok {
+		hostPort = after
+	}
+	if _, _, err := net.SplitHostPort(hostPort); err != nil {
+		hostPort = fmt.Sprintf("%s:%d", hostPort, DefaultSSHPort)
+	}
+	return hostPort
+}
+
+// proxyHostPort returns the "host:port" a client should dial to reach the
+// proxy itself, defaulting to port 443 for an "https" proxy and 80 for any
+// other scheme.
+func proxyHostPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	port := "80"
+	if u.Scheme == "https" {
+		port = "443"
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
@@ -0,0 +1,86 @@
+package giturl
+
+import "testing"
+
+func TestDialAddress(t *testing.T) {
+	testCases := []struct {
+		URL     string
+		Address string
+	}{
+		{URL: "https://127.0.0.1/charlie/wto/bomb.git", Address: "127.0.0.1:443"},
+		{URL: "ssh://git@127.0.0.1:2222/charlie/wto/bomb.git", Address: "127.0.0.1:2222"},
+		{URL: "git@gitlab.com:charlie/wto/bomb.git", Address: "gitlab.com:22"},
+		{URL: "ssh://git@[::1]/charlie/wto/bomb.git", Address: "[::1]:22"},
+	}
+
+	for _, c := range testCases {
+		URL, err := NewGitURL(c.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		if actual, expected := URL.DialAddress(), c.Address; actual != expected {
+			t.Errorf("test failed, actual address: %s, expected: %s", actual, expected)
+		}
+	}
+}
+
+func TestResolveWithPerHostProxy(t *testing.T) {
+	URL, err := NewGitURL("https://127.0.0.1/charlie/wto/bomb.git")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	addr, proxyURL, err := URL.Resolve(ProxyConfig{
+		PerHost: map[string]string{"127.0.0.1": "http://127.0.0.2:8080"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if proxyURL == nil {
+		t.Fatalf("expected a proxy url, got nil")
+	}
+	if actual, expected := addr.String(), "127.0.0.2:8080"; actual != expected {
+		t.Errorf("test failed, actual addr: %s, expected: %s", actual, expected)
+	}
+}
+
+func TestResolveNoProxy(t *testing.T) {
+	URL, err := NewGitURL("https://127.0.0.1/charlie/wto/bomb.git")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	addr, proxyURL, err := URL.Resolve(ProxyConfig{
+		HTTPSProxy: "http://127.0.0.2:8080",
+		NoProxy:    "127.0.0.1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if proxyURL != nil {
+		t.Errorf("test failed, expected no proxy, got %s", proxyURL.String())
+	}
+	if actual, expected := addr.String(), "127.0.0.1:443"; actual != expected {
+		t.Errorf("test failed, actual addr: %s, expected: %s", actual, expected)
+	}
+}
+
+func TestResolveSSHProxyJump(t *testing.T) {
+	URL, err := NewGitURL("git@gitlab.com:charlie/wto/bomb.git")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	addr, proxyURL, err := URL.Resolve(ProxyConfig{
+		ProxyJump: []string{"jumpuser@127.0.0.1:2222"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if proxyURL != nil {
+		t.Errorf("test failed, expected no proxy url for SSH, got %s", proxyURL.String())
+	}
+	if actual, expected := addr.String(), "127.0.0.1:2222"; actual != expected {
+		t.Errorf("test failed, actual addr: %s, expected: %s", actual, expected)
+	}
+}